@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/isaacimp/go-track/task"
+)
+
+const taskConfigFile = "config.json"
+
+var backgroundRunner *task.Runner
+
+// mainJob is a closure a background task wants run on the main goroutine,
+// since that's the only goroutine allowed to touch foods/dailyDiary.
+type mainJob struct {
+	fn   func() error
+	done chan error
+}
+
+// mainJobs carries pending work from background task goroutines to the
+// main goroutine. Tasks never read or write foods/dailyDiary directly;
+// they submit a job here and wait for it to run.
+var mainJobs = make(chan mainJob, 16)
+
+// runOnMain submits fn to be run on the main goroutine and blocks for its
+// result, respecting ctx's deadline in case the main loop is busy (e.g.
+// blocked on a long-running menu) and never gets around to draining.
+func runOnMain(ctx context.Context, fn func() error) error {
+	job := mainJob{fn: fn, done: make(chan error, 1)}
+
+	select {
+	case mainJobs <- job:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-job.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// drainMainJobs runs every job currently queued, without blocking. It
+// must only be called from the main goroutine, and callers should call it
+// often (e.g. once per menu loop iteration) so background tasks don't
+// stall waiting for it.
+func drainMainJobs() {
+	for {
+		select {
+		case job := <-mainJobs:
+			job.done <- job.fn()
+		default:
+			return
+		}
+	}
+}
+
+// loadTaskConfig reads config.json, if present, into a task.Config. A
+// missing file just means no background tasks are configured.
+func loadTaskConfig() (task.Config, error) {
+	file, err := os.Open(taskConfigFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return task.Config{}, nil
+		}
+		return task.Config{}, fmt.Errorf("error opening %s: %v", taskConfigFile, err)
+	}
+	defer file.Close()
+
+	var cfg task.Config
+	if err := json.NewDecoder(file).Decode(&cfg); err != nil {
+		return task.Config{}, fmt.Errorf("error decoding %s: %v", taskConfigFile, err)
+	}
+	return cfg, nil
+}
+
+// buildTasks pairs every TaskConfig declared in cfg with the Func that
+// implements the command it names.
+func buildTasks(cfg task.Config) []task.Task {
+	commands := map[string]task.Func{
+		"recompute-rolling-averages": recomputeRollingAveragesTask,
+		"recompute-derived-fields":   recomputeDerivedFieldsTask,
+		"prune-stale-diary-entries":  pruneStaleDiaryEntriesTask,
+		"sync-push":                  syncPushTask,
+	}
+
+	tasks := make([]task.Task, 0, len(cfg.Tasks))
+	for _, tc := range cfg.Tasks {
+		run, ok := commands[tc.Command.Name]
+		if !ok {
+			log.Printf("Warning: unknown task command %q, skipping", tc.Command.Name)
+			continue
+		}
+		tasks = append(tasks, task.Task{Config: tc, Run: run})
+	}
+	return tasks
+}
+
+// startBackgroundTasks loads config.json and starts every declared task
+// under ctx, returning the Runner so the main menu can report status.
+func startBackgroundTasks(ctx context.Context) (*task.Runner, error) {
+	cfg, err := loadTaskConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	runner := task.NewRunner(buildTasks(cfg))
+	runner.Start(ctx)
+	return runner, nil
+}
+
+// recomputeRollingAveragesTask logs the rolling 7/30-day calorie and cost
+// averages. The figures are only written to the log; nothing else reads
+// them. It reads dailyDiary through runOnMain since that's the only goroutine
+// allowed to touch it.
+func recomputeRollingAveragesTask(ctx context.Context) error {
+	return runOnMain(ctx, func() error {
+		now := time.Now()
+		cal7, cost7 := rollingAverage(now, 7)
+		cal30, cost30 := rollingAverage(now, 30)
+		log.Printf("7d avg: %.0f cal / $%.2f, 30d avg: %.0f cal / $%.2f", cal7, cost7, cal30, cost30)
+		return nil
+	})
+}
+
+// rollingAverage returns the average daily calories and cost over the
+// trailing n days ending on now. Only days with at least one diary entry
+// count toward the average.
+func rollingAverage(now time.Time, n int) (avgCal, avgCost float64) {
+	cutoff := now.AddDate(0, 0, -(n - 1)).Format("2006-01-02")
+	var totalCal, totalCost float64
+	days := make(map[string]bool)
+
+	for _, e := range dailyDiary.Entries {
+		if e.Date < cutoff {
+			continue
+		}
+		totalCal += e.Calories
+		totalCost += e.Cost
+		days[e.Date] = true
+	}
+
+	if len(days) == 0 {
+		return 0, 0
+	}
+	return totalCal / float64(len(days)), totalCost / float64(len(days))
+}
+
+// recomputeDerivedFieldsTask recalculates CalPerDollar/CalPer100g for
+// every food, in case one was edited directly (e.g. via an import) and
+// its derived fields were left stale. It mutates foods through
+// runOnMain since that's the only goroutine allowed to touch it.
+func recomputeDerivedFieldsTask(ctx context.Context) error {
+	return runOnMain(ctx, func() error {
+		changed := false
+		for i := range foods {
+			f := &foods[i]
+			if f.Price <= 0 || f.Quantity <= 0 {
+				continue
+			}
+			calPerDollar := f.Calories / f.Price
+			calPer100g := (f.Calories / float64(f.Quantity)) * 100
+			if math.Abs(calPerDollar-f.CalPerDollar) > 0.01 || math.Abs(calPer100g-f.CalPer100g) > 0.01 {
+				f.CalPerDollar = calPerDollar
+				f.CalPer100g = calPer100g
+				changed = true
+			}
+		}
+
+		if !changed {
+			return nil
+		}
+		return saveToFile()
+	})
+}
+
+// staleDiaryRetentionDays is how long a diary entry is kept before
+// pruneStaleDiaryEntriesTask removes it.
+const staleDiaryRetentionDays = 365
+
+// pruneStaleDiaryEntriesTask drops diary entries older than
+// staleDiaryRetentionDays so the diary doesn't grow without bound. It
+// mutates dailyDiary through runOnMain since that's the only goroutine
+// allowed to touch it.
+func pruneStaleDiaryEntriesTask(ctx context.Context) error {
+	return runOnMain(ctx, func() error {
+		cutoff := time.Now().AddDate(0, 0, -staleDiaryRetentionDays).Format("2006-01-02")
+
+		kept := make([]DiaryEntry, 0, len(dailyDiary.Entries))
+		for _, e := range dailyDiary.Entries {
+			if e.Date >= cutoff {
+				kept = append(kept, e)
+			}
+		}
+		if len(kept) == len(dailyDiary.Entries) {
+			return nil
+		}
+
+		dailyDiary.Entries = kept
+		return saveDiaryToFile()
+	})
+}
+
+// syncPushTask pushes the current foods/diary to the user's configured
+// sync backend, if any. The snapshot copies are taken on the main
+// goroutine via runOnMain; the network call itself then runs on the
+// task's own goroutine against those independent copies, so a slow
+// push never blocks the menu loop.
+func syncPushTask(ctx context.Context) error {
+	if currentUser.Token == "" {
+		return nil
+	}
+
+	var foodsSnapshot []Food
+	var diarySnapshot DailyDiary
+	err := runOnMain(ctx, func() error {
+		foodsSnapshot = append([]Food(nil), foods...)
+		diarySnapshot = DailyDiary{Entries: append([]DiaryEntry(nil), dailyDiary.Entries...)}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	backend := NewHTTPSyncBackend(syncBaseURL, currentUser)
+	return backend.Push(ctx, foodsSnapshot, diarySnapshot)
+}
+
+// showTaskStatus prints the last-run time and error for every background
+// task, for the main menu's status view.
+func showTaskStatus() {
+	if backgroundRunner == nil {
+		fmt.Printf("Background tasks not started (%s could not be loaded).\n", taskConfigFile)
+		return
+	}
+
+	report := backgroundRunner.StatusReport()
+	sort.Slice(report, func(i, j int) bool { return report[i].Name < report[j].Name })
+
+	fmt.Println("\n=== Background Task Status ===")
+	for _, s := range report {
+		if s.LastRun.IsZero() {
+			fmt.Printf("%s: not yet run\n", s.Name)
+			continue
+		}
+		if s.LastErr != nil {
+			fmt.Printf("%s: last ran %s, error: %v\n", s.Name, s.LastRun.Format(time.RFC3339), s.LastErr)
+			continue
+		}
+		fmt.Printf("%s: last ran %s, ok\n", s.Name, s.LastRun.Format(time.RFC3339))
+	}
+}
@@ -0,0 +1,390 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	ui "github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+)
+
+// correlationMinN and correlationMinR are the thresholds a
+// (food, symptom, lag) pair must clear to be reported: enough samples to
+// trust the statistic, and a correlation strong enough to be worth a
+// user's attention.
+const (
+	correlationMinN = 14
+	correlationMinR = 0.3
+)
+
+// correlationMaxLag is the largest number of days of lag considered,
+// e.g. whether yesterday's dairy intake correlates with today's headache
+// severity.
+const correlationMaxLag = 3
+
+// DayAggregate summarizes one day of diary entries: totals plus a
+// per-food gram breakdown, so a single food's intake can be correlated
+// against symptom severity independently of the day's overall total.
+type DayAggregate struct {
+	TotalCalories float64
+	TotalCost     float64
+	GramsByFood   map[string]float64
+}
+
+// CorrelationResult is one row of the ranked correlation table: how food
+// (or "total calories"/"total cost") at lagDays correlates with symptom.
+type CorrelationResult struct {
+	Food     string
+	Symptom  string
+	LagDays  int
+	Pearson  float64
+	Spearman float64
+	PValue   float64
+	N        int
+}
+
+// buildDayAggregates groups dailyDiary.Entries by date.
+func buildDayAggregates() map[string]*DayAggregate {
+	days := make(map[string]*DayAggregate)
+	for _, e := range dailyDiary.Entries {
+		d, ok := days[e.Date]
+		if !ok {
+			d = &DayAggregate{GramsByFood: make(map[string]float64)}
+			days[e.Date] = d
+		}
+		d.TotalCalories += e.Calories
+		d.TotalCost += e.Cost
+		d.GramsByFood[e.FoodName] += float64(e.Quantity)
+	}
+	return days
+}
+
+// symptomSeverityByDate groups symptomDiary.Entries for one symptom name
+// by date, taking the max severity logged that day.
+func symptomSeverityByDate(symptomName string) map[string]float64 {
+	severities := make(map[string]float64)
+	for _, e := range symptomDiary.Entries {
+		if e.SymptomName != symptomName {
+			continue
+		}
+		if existing, ok := severities[e.Date]; !ok || float64(e.Severity) > existing {
+			severities[e.Date] = float64(e.Severity)
+		}
+	}
+	return severities
+}
+
+// symptomNames returns the distinct symptom names tracked in
+// symptomDiary, sorted for deterministic output.
+func symptomNames() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, s := range symptomDiary.Symptoms {
+		if !seen[s.Name] {
+			seen[s.Name] = true
+			names = append(names, s.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// intakeSeries returns, for the dates in lookback (oldest first), the
+// intake value for subject ("total calories", "total cost", or a food
+// name) shifted back by lagDays, aligned with a symptom severity reading
+// on that date. A date is skipped when it has no severity reading or
+// when the diary has no entries lagDays earlier.
+func intakeSeries(days map[string]*DayAggregate, severities map[string]float64, lookback []string, subject string, lagDays int) (xs, ys []float64) {
+	for _, date := range lookback {
+		t, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			continue
+		}
+		intakeDate := t.AddDate(0, 0, -lagDays).Format("2006-01-02")
+
+		severity, ok := severities[date]
+		if !ok {
+			continue
+		}
+
+		agg, ok := days[intakeDate]
+		if !ok {
+			continue
+		}
+
+		var value float64
+		switch subject {
+		case "total calories":
+			value = agg.TotalCalories
+		case "total cost":
+			value = agg.TotalCost
+		default:
+			value = agg.GramsByFood[subject]
+		}
+
+		xs = append(xs, value)
+		ys = append(ys, severity)
+	}
+	return xs, ys
+}
+
+// intakeSubjects returns every subject worth correlating: the two daily
+// totals plus every distinct food name logged in the diary.
+func intakeSubjects() []string {
+	subjects := []string{"total calories", "total cost"}
+	seen := make(map[string]bool)
+	for _, e := range dailyDiary.Entries {
+		if !seen[e.FoodName] {
+			seen[e.FoodName] = true
+			subjects = append(subjects, e.FoodName)
+		}
+	}
+	return subjects
+}
+
+// computeCorrelations scans every (subject, symptom, lag) combination
+// over the trailing lookbackDays, keeping only results with at least
+// correlationMinN samples and |pearson| > correlationMinR, ranked by
+// |pearson| descending.
+func computeCorrelations(lookbackDays int) []CorrelationResult {
+	days := buildDayAggregates()
+	lookback := recentDates(lookbackDays)
+	subjects := intakeSubjects()
+
+	var results []CorrelationResult
+	for _, symptom := range symptomNames() {
+		severities := symptomSeverityByDate(symptom)
+
+		for _, subject := range subjects {
+			for lag := 0; lag <= correlationMaxLag; lag++ {
+				xs, ys := intakeSeries(days, severities, lookback, subject, lag)
+				if len(xs) < correlationMinN {
+					continue
+				}
+
+				r := pearson(xs, ys)
+				if math.Abs(r) <= correlationMinR {
+					continue
+				}
+
+				results = append(results, CorrelationResult{
+					Food:     subject,
+					Symptom:  symptom,
+					LagDays:  lag,
+					Pearson:  r,
+					Spearman: spearman(xs, ys),
+					PValue:   permutationPValue(xs, ys),
+					N:        len(xs),
+				})
+			}
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return math.Abs(results[i].Pearson) > math.Abs(results[j].Pearson)
+	})
+	return results
+}
+
+// pearson computes the Pearson correlation coefficient of xs and ys.
+func pearson(xs, ys []float64) float64 {
+	n := float64(len(xs))
+	if n == 0 {
+		return 0
+	}
+
+	var sumX, sumY, sumXY, sumX2, sumY2 float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+		sumXY += xs[i] * ys[i]
+		sumX2 += xs[i] * xs[i]
+		sumY2 += ys[i] * ys[i]
+	}
+
+	numerator := n*sumXY - sumX*sumY
+	denominator := math.Sqrt((n*sumX2 - sumX*sumX) * (n*sumY2 - sumY*sumY))
+	if denominator == 0 {
+		return 0
+	}
+	return numerator / denominator
+}
+
+// spearman computes the Spearman rank correlation: Pearson's correlation
+// applied to each series' ranks.
+func spearman(xs, ys []float64) float64 {
+	return pearson(ranks(xs), ranks(ys))
+}
+
+// ranks returns the 1-based rank of each element of vs, averaging ranks
+// across ties.
+func ranks(vs []float64) []float64 {
+	type indexed struct {
+		value float64
+		index int
+	}
+	sorted := make([]indexed, len(vs))
+	for i, v := range vs {
+		sorted[i] = indexed{value: v, index: i}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].value < sorted[j].value })
+
+	result := make([]float64, len(vs))
+	i := 0
+	for i < len(sorted) {
+		j := i
+		for j < len(sorted) && sorted[j].value == sorted[i].value {
+			j++
+		}
+		avgRank := float64(i+j+1) / 2 // average of ranks i+1..j (1-based)
+		for k := i; k < j; k++ {
+			result[sorted[k].index] = avgRank
+		}
+		i = j
+	}
+	return result
+}
+
+// permutationPValue runs a two-sided permutation test on the correlation
+// between xs and ys: Welch's t-test assumes near-normal data, which
+// rarely holds for the small, skewed samples typical of personal
+// tracking, so we instead shuffle ys repeatedly and see how often a
+// random pairing produces as strong a correlation as the real one.
+func permutationPValue(xs, ys []float64) float64 {
+	const permutations = 1000
+	observed := math.Abs(pearson(xs, ys))
+
+	shuffled := make([]float64, len(ys))
+	copy(shuffled, ys)
+
+	extreme := 0
+	for p := 0; p < permutations; p++ {
+		shufflePermutation(shuffled, p)
+		if math.Abs(pearson(xs, shuffled)) >= observed {
+			extreme++
+		}
+	}
+	return float64(extreme) / float64(permutations)
+}
+
+// shufflePermutation deterministically permutes vs in place using seed,
+// avoiding math/rand so results are reproducible across the identical
+// seed sequence 0..permutations-1.
+func shufflePermutation(vs []float64, seed int) {
+	state := uint32(seed*2654435761 + 1)
+	for i := len(vs) - 1; i > 0; i-- {
+		state = state*1103515245 + 12345
+		j := int(state) % (i + 1)
+		if j < 0 {
+			j += i + 1
+		}
+		vs[i], vs[j] = vs[j], vs[i]
+	}
+}
+
+// exportCorrelationsCSV writes results to path with header
+// food,symptom,lag_days,pearson_r,spearman_r,p_value,n.
+func exportCorrelationsCSV(path string, results []CorrelationResult) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating CSV file: %v", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	header := []string{"food", "symptom", "lag_days", "pearson_r", "spearman_r", "p_value", "n"}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("error writing CSV header: %v", err)
+	}
+
+	for _, r := range results {
+		row := []string{
+			r.Food,
+			r.Symptom,
+			strconv.Itoa(r.LagDays),
+			strconv.FormatFloat(r.Pearson, 'f', 3, 64),
+			strconv.FormatFloat(r.Spearman, 'f', 3, 64),
+			strconv.FormatFloat(r.PValue, 'f', 3, 64),
+			strconv.Itoa(r.N),
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("error writing CSV row: %v", err)
+		}
+	}
+	return nil
+}
+
+// correlationLookbackDays is how far back computeCorrelations looks for
+// the interactive dashboard view.
+const correlationLookbackDays = 90
+
+// runCorrelationDashboard renders computeCorrelations' ranked table in
+// termui, with "e" exporting the same results to a CSV file.
+func runCorrelationDashboard() (err error) {
+	results := computeCorrelations(correlationLookbackDays)
+
+	if err := ui.Init(); err != nil {
+		return fmt.Errorf("failed to initialize termui: %v", err)
+	}
+
+	// ui.Close() isn't idempotent, so closeOnce guards against the
+	// recover handler and the normal-return defer both firing: whichever
+	// runs first does the real close, the other is a no-op.
+	var closeOnce sync.Once
+	closeUI := func() { closeOnce.Do(ui.Close) }
+	defer closeUI()
+	defer func() {
+		if r := recover(); r != nil {
+			closeUI()
+			panic(r)
+		}
+	}()
+
+	table := widgets.NewTable()
+	table.Title = "Diet <-> Symptom Correlations (e: export CSV, q: quit)"
+	table.Rows = append([][]string{{"Food", "Symptom", "Lag (days)", "Pearson r", "Spearman r", "p-value", "n"}}, correlationRows(results)...)
+	table.SetRect(0, 0, 100, 2+len(table.Rows))
+
+	ui.Render(table)
+
+	uiEvents := ui.PollEvents()
+	for {
+		e := <-uiEvents
+		switch e.ID {
+		case "q", "<C-c>", "<Escape>":
+			return nil
+		case "e":
+			if err := exportCorrelationsCSV("correlations.csv", results); err != nil {
+				table.Title = fmt.Sprintf("Export failed: %v", err)
+			} else {
+				table.Title = "Exported to correlations.csv (q: quit)"
+			}
+			ui.Render(table)
+		}
+	}
+}
+
+// correlationRows formats results as table cell rows.
+func correlationRows(results []CorrelationResult) [][]string {
+	rows := make([][]string, len(results))
+	for i, r := range results {
+		rows[i] = []string{
+			r.Food,
+			r.Symptom,
+			strconv.Itoa(r.LagDays),
+			strconv.FormatFloat(r.Pearson, 'f', 2, 64),
+			strconv.FormatFloat(r.Spearman, 'f', 2, 64),
+			strconv.FormatFloat(r.PValue, 'f', 3, 64),
+			strconv.Itoa(r.N),
+		}
+	}
+	return rows
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func floatsClose(a, b, tol float64) bool {
+	return math.Abs(a-b) <= tol
+}
+
+func TestPearsonPerfectCorrelation(t *testing.T) {
+	xs := []float64{1, 2, 3, 4, 5}
+	ys := []float64{2, 4, 6, 8, 10}
+	if r := pearson(xs, ys); !floatsClose(r, 1, 1e-9) {
+		t.Errorf("pearson(%v, %v) = %v, want 1", xs, ys, r)
+	}
+}
+
+func TestPearsonPerfectAntiCorrelation(t *testing.T) {
+	xs := []float64{1, 2, 3, 4, 5}
+	ys := []float64{10, 8, 6, 4, 2}
+	if r := pearson(xs, ys); !floatsClose(r, -1, 1e-9) {
+		t.Errorf("pearson(%v, %v) = %v, want -1", xs, ys, r)
+	}
+}
+
+func TestPearsonNoVariance(t *testing.T) {
+	xs := []float64{1, 1, 1, 1}
+	ys := []float64{1, 2, 3, 4}
+	if r := pearson(xs, ys); r != 0 {
+		t.Errorf("pearson with zero-variance input = %v, want 0", r)
+	}
+}
+
+func TestRanksAveragesTies(t *testing.T) {
+	// The two 10s tie for ranks 1-2, averaging to 1.5 each; 20 is rank 3
+	// and 30 is rank 4.
+	got := ranks([]float64{10, 20, 10, 30})
+	want := []float64{1.5, 3, 1.5, 4}
+	for i := range want {
+		if !floatsClose(got[i], want[i], 1e-9) {
+			t.Errorf("ranks = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func TestSpearmanMonotonicButNonlinear(t *testing.T) {
+	// Monotonic but not linear: Spearman should still read as a perfect
+	// correlation even though Pearson would not.
+	xs := []float64{1, 2, 3, 4, 5}
+	ys := []float64{1, 4, 9, 16, 25}
+	if r := spearman(xs, ys); !floatsClose(r, 1, 1e-9) {
+		t.Errorf("spearman(%v, %v) = %v, want 1", xs, ys, r)
+	}
+}
+
+func TestShufflePermutationIsDeterministic(t *testing.T) {
+	a := []float64{1, 2, 3, 4, 5}
+	b := []float64{1, 2, 3, 4, 5}
+	shufflePermutation(a, 7)
+	shufflePermutation(b, 7)
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("shufflePermutation not deterministic for the same seed: %v vs %v", a, b)
+		}
+	}
+}
+
+func TestShufflePermutationIsAPermutation(t *testing.T) {
+	vs := []float64{1, 2, 3, 4, 5, 6, 7, 8}
+	shufflePermutation(vs, 3)
+
+	seen := make(map[float64]bool)
+	for _, v := range vs {
+		if v < 1 || v > 8 || seen[v] {
+			t.Fatalf("shufflePermutation produced a non-permutation: %v", vs)
+		}
+		seen[v] = true
+	}
+}
+
+func TestPermutationPValuePerfectCorrelationIsSignificant(t *testing.T) {
+	xs := []float64{1, 2, 3, 4, 5, 6, 7, 8}
+	ys := []float64{1, 2, 3, 4, 5, 6, 7, 8}
+	p := permutationPValue(xs, ys)
+	if p > 0.05 {
+		t.Errorf("permutationPValue for a perfect correlation = %v, want a small p-value", p)
+	}
+}
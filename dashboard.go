@@ -0,0 +1,273 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	ui "github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+)
+
+// dashboardLookbackDays controls how many trailing days the calorie
+// BarChart and spend Sparkline cover.
+const dashboardLookbackDays = 14
+
+// Panes that runDashboard's <Tab> key cycles focus between.
+const (
+	focusCalendar = iota
+	focusTable
+	focusSymptoms
+	numDashboardPanes
+)
+
+// dashboardState tracks which date is selected, which pane has focus, and
+// any in-progress search filter.
+type dashboardState struct {
+	dates           []string // oldest to newest, length dashboardLookbackDays
+	selected        int      // index into dates
+	focus           int      // one of focusCalendar/focusTable/focusSymptoms
+	symptomSelected int      // index into the symptom panel's current rows
+	symptomRowCount int
+	searching       bool
+	searchQuery     string
+}
+
+// moveFocused moves the selection of whichever pane currently has focus
+// by delta, clamped to that pane's bounds. The table pane has no
+// navigable selection of its own.
+func (s *dashboardState) moveFocused(delta int) {
+	switch s.focus {
+	case focusCalendar:
+		if next := s.selected + delta; next >= 0 && next < len(s.dates) {
+			s.selected = next
+		}
+	case focusSymptoms:
+		if next := s.symptomSelected + delta; next >= 0 && next < s.symptomRowCount {
+			s.symptomSelected = next
+		}
+	}
+}
+
+// dayAggregate holds the totals backing the BarChart and Sparkline for a
+// single day.
+type dayAggregate struct {
+	calories float64
+	cost     float64
+}
+
+// aggregateByDate buckets dailyDiary.Entries into per-day totals.
+func aggregateByDate() map[string]dayAggregate {
+	totals := make(map[string]dayAggregate)
+	for _, e := range dailyDiary.Entries {
+		a := totals[e.Date]
+		a.calories += e.Calories
+		a.cost += e.Cost
+		totals[e.Date] = a
+	}
+	return totals
+}
+
+// recentDates returns the last n calendar dates, oldest first, ending
+// today.
+func recentDates(n int) []string {
+	dates := make([]string, n)
+	today := time.Now()
+	for i := 0; i < n; i++ {
+		dates[i] = today.AddDate(0, 0, -(n - 1 - i)).Format("2006-01-02")
+	}
+	return dates
+}
+
+// runDashboard launches the interactive termui dashboard: a calendar/date
+// picker, a BarChart of daily calories, a Table of the selected day's
+// diary entries, a Sparkline of spend-per-day, and a side panel
+// overlaying symptom entries on the same timeline. It replaces the old
+// scrolling text menus for handleFoodMenu and handleCompareMenu.
+func runDashboard() (err error) {
+	if err := ui.Init(); err != nil {
+		return fmt.Errorf("failed to initialize termui: %v", err)
+	}
+
+	// ui.Close() isn't idempotent, so closeOnce guards against the
+	// recover handler and the normal-return defer both firing: whichever
+	// runs first does the real close, the other is a no-op.
+	var closeOnce sync.Once
+	closeUI := func() { closeOnce.Do(ui.Close) }
+	defer closeUI()
+
+	// However the dashboard exits, make sure termui tears down cleanly
+	// instead of leaving the terminal in raw mode.
+	defer func() {
+		if r := recover(); r != nil {
+			closeUI()
+			panic(r)
+		}
+	}()
+
+	state := &dashboardState{
+		dates:    recentDates(dashboardLookbackDays),
+		selected: dashboardLookbackDays - 1, // today
+	}
+
+	calendar := widgets.NewList()
+	calendar.Title = "Calendar (j/k, tab)"
+
+	barChart := widgets.NewBarChart()
+	barChart.Title = fmt.Sprintf("Calories (last %d days)", dashboardLookbackDays)
+
+	table := widgets.NewTable()
+	table.Title = "Diary"
+
+	spark := widgets.NewSparkline()
+	spark.LineColor = ui.ColorGreen
+	sparkGroup := widgets.NewSparklineGroup(spark)
+	sparkGroup.Title = "Spend per day"
+
+	symptomList := widgets.NewList()
+	symptomList.Title = "Symptoms"
+
+	layoutDashboard(calendar, barChart, table, sparkGroup, symptomList)
+	refreshDashboard(state, calendar, barChart, table, sparkGroup, symptomList)
+
+	uiEvents := ui.PollEvents()
+	for {
+		e := <-uiEvents
+		if state.searching {
+			// While searching, every other keybinding is suspended: a
+			// typed "j", "k", or "q" is query text, not navigation.
+			switch e.ID {
+			case "<Escape>", "<C-c>":
+				state.searching = false
+				state.searchQuery = ""
+			case "<Enter>":
+				state.searching = false
+			case "<Backspace>":
+				if len(state.searchQuery) > 0 {
+					state.searchQuery = state.searchQuery[:len(state.searchQuery)-1]
+				}
+			default:
+				if len(e.ID) == 1 {
+					state.searchQuery += e.ID
+				}
+			}
+		} else {
+			switch e.ID {
+			case "q", "<C-c>", "<Escape>":
+				return nil
+			case "<Tab>":
+				state.focus = (state.focus + 1) % numDashboardPanes
+			case "j", "<Down>":
+				state.moveFocused(1)
+			case "k", "<Up>":
+				state.moveFocused(-1)
+			case "/":
+				state.searching = true
+			}
+		}
+		refreshDashboard(state, calendar, barChart, table, sparkGroup, symptomList)
+	}
+}
+
+// layoutDashboard positions the panes in a multi-pane grid: calendar and
+// symptom panel on the left, BarChart/Table/Sparkline stacked on the
+// right.
+func layoutDashboard(calendar *widgets.List, barChart *widgets.BarChart, table *widgets.Table, sparkGroup *widgets.SparklineGroup, symptomList *widgets.List) {
+	calendar.SetRect(0, 0, 24, 18)
+	symptomList.SetRect(0, 18, 24, 36)
+	barChart.SetRect(24, 0, 90, 14)
+	table.SetRect(24, 14, 90, 28)
+	sparkGroup.SetRect(24, 28, 90, 36)
+}
+
+// focusedBorderStyle returns the border style a pane should use: cyan and
+// bold when it has focus, the default otherwise. This is the only visible
+// sign of which pane <Tab> has selected.
+func focusedBorderStyle(focused bool) ui.Style {
+	if focused {
+		return ui.NewStyle(ui.ColorCyan, ui.ColorClear, ui.ModifierBold)
+	}
+	return ui.NewStyle(ui.ColorWhite)
+}
+
+// refreshDashboard recomputes every pane's contents from the current
+// dailyDiary/symptomDiary state and the given selection, then renders.
+func refreshDashboard(state *dashboardState, calendar *widgets.List, barChart *widgets.BarChart, table *widgets.Table, sparkGroup *widgets.SparklineGroup, symptomList *widgets.List) {
+	calendar.BorderStyle = focusedBorderStyle(state.focus == focusCalendar)
+	table.BorderStyle = focusedBorderStyle(state.focus == focusTable)
+	symptomList.BorderStyle = focusedBorderStyle(state.focus == focusSymptoms)
+
+	totals := aggregateByDate()
+
+	calendar.Rows = make([]string, len(state.dates))
+	barChart.Labels = make([]string, len(state.dates))
+	barChart.Data = make([]float64, len(state.dates))
+	spendSeries := make([]float64, len(state.dates))
+	for i, date := range state.dates {
+		marker := "  "
+		if i == state.selected {
+			marker = "> "
+		}
+		calendar.Rows[i] = marker + date
+		barChart.Labels[i] = date[5:] // MM-DD
+		barChart.Data[i] = totals[date].calories
+		spendSeries[i] = totals[date].cost
+	}
+	calendar.SelectedRow = state.selected
+	sparkGroup.Sparklines[0].Data = spendSeries
+
+	selectedDate := state.dates[state.selected]
+	table.Rows = [][]string{{"Food", "Quantity", "Calories", "Cost"}}
+	for _, e := range dailyDiary.Entries {
+		if e.Date != selectedDate {
+			continue
+		}
+		if state.searching && state.searchQuery != "" &&
+			!strings.Contains(strings.ToLower(e.FoodName), strings.ToLower(state.searchQuery)) {
+			continue
+		}
+		table.Rows = append(table.Rows, []string{
+			e.FoodName,
+			fmt.Sprintf("%dg", e.Quantity),
+			fmt.Sprintf("%.0f", e.Calories),
+			fmt.Sprintf("$%.2f", e.Cost),
+		})
+	}
+	if state.searching {
+		table.Title = fmt.Sprintf("Diary (search: %s)", state.searchQuery)
+	} else {
+		table.Title = "Diary"
+	}
+
+	rows := symptomEntriesOn(selectedDate)
+	symptomList.Rows = rows
+	state.symptomRowCount = len(rows)
+	if state.symptomSelected >= state.symptomRowCount {
+		state.symptomSelected = state.symptomRowCount - 1
+	}
+	if state.symptomSelected < 0 {
+		state.symptomSelected = 0
+	}
+	symptomList.SelectedRow = state.symptomSelected
+
+	ui.Render(calendar, barChart, table, sparkGroup, symptomList)
+}
+
+// symptomEntriesOn renders the symptom entries logged for date as display
+// rows, sorted by symptom name.
+func symptomEntriesOn(date string) []string {
+	var rows []string
+	for _, entry := range symptomDiary.Entries {
+		if entry.Date != date {
+			continue
+		}
+		rows = append(rows, fmt.Sprintf("%s: %d/10", entry.SymptomName, entry.Severity))
+	}
+	sort.Strings(rows)
+	if len(rows) == 0 {
+		rows = []string{"No symptoms logged"}
+	}
+	return rows
+}
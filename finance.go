@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+)
+
+// totalSpend returns the lifetime total cost of every diary entry.
+func totalSpend() float64 {
+	var total float64
+	for _, e := range dailyDiary.Entries {
+		total += e.Cost
+	}
+	return total
+}
+
+// spendByDate sums diary entry costs for a single date.
+func spendByDate(date string) float64 {
+	var total float64
+	for _, e := range dailyDiary.Entries {
+		if e.Date == date {
+			total += e.Cost
+		}
+	}
+	return total
+}
+
+func viewTotalSpend() {
+	fmt.Printf("\nTotal spend: $%.2f\n", totalSpend())
+}
+
+func viewSpendByDate() {
+	dateStr := readInput("Enter date (YYYY-MM-DD): ")
+	fmt.Printf("\nSpend on %s: $%.2f\n", dateStr, spendByDate(dateStr))
+}
+
+func showFinanceMenu() {
+	fmt.Println("\n=== Finance Menu ===")
+	fmt.Println("1. View total spend")
+	fmt.Println("2. View spend by date")
+	fmt.Println("3. Return to Main Menu")
+	fmt.Print("Choose an option: ")
+}
+
+func HandleFinanceMenu() {
+	for {
+		drainMainJobs()
+		showFinanceMenu()
+		choice := readInput("")
+
+		switch choice {
+		case "1":
+			viewTotalSpend()
+		case "2":
+			viewSpendByDate()
+		case "3":
+			return
+		default:
+			fmt.Println("Invalid option. Please try again.")
+		}
+	}
+}
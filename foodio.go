@@ -0,0 +1,406 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/tealeg/xlsx"
+)
+
+// mergeAction describes what to do when an imported food collides with an
+// existing one (same name and quantity).
+type mergeAction int
+
+const (
+	mergeSkip mergeAction = iota
+	mergeOverwrite
+	mergeMerge
+)
+
+// findDuplicateFood returns the index of an existing food with the same
+// name and quantity as candidate, or -1 if there is no duplicate.
+func findDuplicateFood(candidate Food) int {
+	for i, f := range foods {
+		if f.Name == candidate.Name && f.Quantity == candidate.Quantity {
+			return i
+		}
+	}
+	return -1
+}
+
+// promptMergeAction asks the user how to handle a duplicate food found
+// during import.
+func promptMergeAction(name string) mergeAction {
+	fmt.Printf("\n%q already exists with the same quantity.\n", name)
+	choice := readInput("Merge (m), overwrite (o) or skip (s)? [s]: ")
+	switch choice {
+	case "m", "M":
+		return mergeMerge
+	case "o", "O":
+		return mergeOverwrite
+	default:
+		return mergeSkip
+	}
+}
+
+// resolveDuplicate prompts for a mergeAction and applies it to the
+// existing food at index idx, given the freshly imported candidate.
+func resolveDuplicate(idx int, candidate Food) {
+	switch promptMergeAction(candidate.Name) {
+	case mergeOverwrite:
+		candidate.ID = foods[idx].ID
+		candidate.UserID = foods[idx].UserID
+		foods[idx] = candidate
+	case mergeMerge:
+		existing := foods[idx]
+		existing.Price += candidate.Price
+		existing.Calories += candidate.Calories
+		existing.Quantity += candidate.Quantity
+		existing.CalPerDollar = existing.Calories / existing.Price
+		existing.CalPer100g = (existing.Calories / float64(existing.Quantity)) * 100
+		foods[idx] = existing
+	case mergeSkip:
+		// leave the existing food untouched
+	}
+}
+
+// addImportedFood appends candidate as a new food, assigning it the next ID.
+func addImportedFood(candidate Food) {
+	maxID := 0
+	for _, f := range foods {
+		if f.ID > maxID {
+			maxID = f.ID
+		}
+	}
+	candidate.ID = maxID + 1
+	candidate.UserID = currentUser.Username
+	foods = append(foods, candidate)
+}
+
+// importFoodsCSV reads foods from a CSV file with header
+// name,price,calories,quantity and merges them into the food database,
+// prompting on duplicates. This is the easiest way to bootstrap the
+// database from a spreadsheet like a USDA export instead of hand-entering
+// every item through addFoodToDatabase.
+func importFoodsCSV(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error opening CSV file: %v", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return fmt.Errorf("error reading CSV file: %v", err)
+	}
+	if len(rows) < 2 {
+		return nil
+	}
+
+	for _, row := range rows[1:] {
+		if len(row) < 4 {
+			continue
+		}
+		price, err := strconv.ParseFloat(row[1], 64)
+		if err != nil {
+			continue
+		}
+		calories, err := strconv.ParseFloat(row[2], 64)
+		if err != nil {
+			continue
+		}
+		quantity, err := strconv.Atoi(row[3])
+		if err != nil {
+			continue
+		}
+
+		candidate := Food{
+			Name:         row[0],
+			Price:        price,
+			Calories:     calories,
+			Quantity:     quantity,
+			CalPerDollar: calories / price,
+			CalPer100g:   (calories / float64(quantity)) * 100,
+		}
+
+		if idx := findDuplicateFood(candidate); idx != -1 {
+			resolveDuplicate(idx, candidate)
+			continue
+		}
+		addImportedFood(candidate)
+	}
+
+	return saveToFile()
+}
+
+// exportFoodsCSV writes the current food database to a CSV file with
+// header name,price,calories,quantity,cal_per_dollar,cal_per_100g.
+func exportFoodsCSV(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating CSV file: %v", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	header := []string{"name", "price", "calories", "quantity", "cal_per_dollar", "cal_per_100g"}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("error writing CSV header: %v", err)
+	}
+
+	for _, f := range foods {
+		row := []string{
+			f.Name,
+			strconv.FormatFloat(f.Price, 'f', 2, 64),
+			strconv.FormatFloat(f.Calories, 'f', 0, 64),
+			strconv.Itoa(f.Quantity),
+			strconv.FormatFloat(f.CalPerDollar, 'f', 0, 64),
+			strconv.FormatFloat(f.CalPer100g, 'f', 0, 64),
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("error writing CSV row: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// importDiaryCSV reads diary entries from a CSV file with header
+// date,food_name,quantity and appends an entry, dated by the row's date
+// column, for every row whose food is found by name in the food
+// database, using that food to fill in calories and cost.
+func importDiaryCSV(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error opening CSV file: %v", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return fmt.Errorf("error reading CSV file: %v", err)
+	}
+	if len(rows) < 2 {
+		return nil
+	}
+
+	for _, row := range rows[1:] {
+		if len(row) < 3 {
+			continue
+		}
+		date, foodName, quantityStr := row[0], row[1], row[2]
+		quantity, err := strconv.Atoi(quantityStr)
+		if err != nil {
+			continue
+		}
+
+		var matched Food
+		found := false
+		for _, f := range foods {
+			if f.Name == foodName {
+				matched = f
+				found = true
+				break
+			}
+		}
+		if !found {
+			continue
+		}
+
+		ratio := float64(quantity) / float64(matched.Quantity)
+		dailyDiary.Entries = append(dailyDiary.Entries, DiaryEntry{
+			ID:       len(dailyDiary.Entries) + 1,
+			UserID:   currentUser.Username,
+			Date:     date,
+			FoodID:   matched.ID,
+			FoodName: matched.Name,
+			Quantity: quantity,
+			Calories: matched.Calories * ratio,
+			Cost:     matched.Price * ratio,
+		})
+	}
+
+	return saveDiaryToFile()
+}
+
+// importFoodsXLSX reads foods from the first sheet of an XLSX workbook,
+// expecting the same columns as importFoodsCSV with a header row.
+func importFoodsXLSX(path string) error {
+	wb, err := xlsx.OpenFile(path)
+	if err != nil {
+		return fmt.Errorf("error opening XLSX file: %v", err)
+	}
+	if len(wb.Sheets) == 0 {
+		return fmt.Errorf("XLSX file has no sheets")
+	}
+
+	sheet := wb.Sheets[0]
+	for i, row := range sheet.Rows {
+		if i == 0 || row == nil || len(row.Cells) < 4 {
+			continue
+		}
+		name := row.Cells[0].String()
+		price, err := row.Cells[1].Float()
+		if err != nil {
+			continue
+		}
+		calories, err := row.Cells[2].Float()
+		if err != nil {
+			continue
+		}
+		quantity, err := row.Cells[3].Int()
+		if err != nil {
+			continue
+		}
+
+		candidate := Food{
+			Name:         name,
+			Price:        price,
+			Calories:     calories,
+			Quantity:     quantity,
+			CalPerDollar: calories / price,
+			CalPer100g:   (calories / float64(quantity)) * 100,
+		}
+
+		if idx := findDuplicateFood(candidate); idx != -1 {
+			resolveDuplicate(idx, candidate)
+			continue
+		}
+		addImportedFood(candidate)
+	}
+
+	return saveToFile()
+}
+
+// exportFoodsXLSX writes foods, diary (with per-day subtotals) and a
+// computed "Calories per Dollar ranking" into separate, styled sheets of
+// a single XLSX workbook.
+func exportFoodsXLSX(path string) error {
+	wb := xlsx.NewFile()
+
+	if err := writeFoodsSheet(wb); err != nil {
+		return err
+	}
+	if err := writeDiarySheet(wb); err != nil {
+		return err
+	}
+	if err := writeRankingSheet(wb); err != nil {
+		return err
+	}
+
+	if err := wb.Save(path); err != nil {
+		return fmt.Errorf("error saving XLSX file: %v", err)
+	}
+	return nil
+}
+
+// headerStyle bolds and underlines a sheet's header row.
+func headerStyle() *xlsx.Style {
+	style := xlsx.NewStyle()
+	style.Font.Bold = true
+	style.Font.Underline = true
+	return style
+}
+
+func writeHeaderRow(sheet *xlsx.Sheet, headers []string) {
+	style := headerStyle()
+	row := sheet.AddRow()
+	for _, h := range headers {
+		cell := row.AddCell()
+		cell.Value = h
+		cell.SetStyle(style)
+	}
+}
+
+func writeFoodsSheet(wb *xlsx.File) error {
+	sheet, err := wb.AddSheet("Foods")
+	if err != nil {
+		return fmt.Errorf("error creating Foods sheet: %v", err)
+	}
+
+	writeHeaderRow(sheet, []string{"Name", "Price", "Calories", "Quantity (g)", "Cal/Dollar", "Cal/100g"})
+	for _, f := range foods {
+		row := sheet.AddRow()
+		row.AddCell().SetValue(f.Name)
+		row.AddCell().SetFloat(f.Price)
+		row.AddCell().SetFloat(f.Calories)
+		row.AddCell().SetInt(f.Quantity)
+		row.AddCell().SetFloat(f.CalPerDollar)
+		row.AddCell().SetFloat(f.CalPer100g)
+	}
+	return nil
+}
+
+func writeDiarySheet(wb *xlsx.File) error {
+	sheet, err := wb.AddSheet("Diary")
+	if err != nil {
+		return fmt.Errorf("error creating Diary sheet: %v", err)
+	}
+
+	writeHeaderRow(sheet, []string{"Date", "Food", "Quantity (g)", "Calories", "Cost"})
+
+	subtotals := make(map[string]struct {
+		calories float64
+		cost     float64
+	})
+	order := make([]string, 0)
+	for _, e := range dailyDiary.Entries {
+		row := sheet.AddRow()
+		row.AddCell().SetValue(e.Date)
+		row.AddCell().SetValue(e.FoodName)
+		row.AddCell().SetInt(e.Quantity)
+		row.AddCell().SetFloat(e.Calories)
+		row.AddCell().SetFloat(e.Cost)
+
+		if _, ok := subtotals[e.Date]; !ok {
+			order = append(order, e.Date)
+		}
+		totals := subtotals[e.Date]
+		totals.calories += e.Calories
+		totals.cost += e.Cost
+		subtotals[e.Date] = totals
+	}
+
+	for _, date := range order {
+		totals := subtotals[date]
+		row := sheet.AddRow()
+		row.AddCell().SetValue(fmt.Sprintf("%s subtotal", date))
+		row.AddCell().SetValue("")
+		row.AddCell().SetValue("")
+		row.AddCell().SetFloat(totals.calories)
+		row.AddCell().SetFloat(totals.cost)
+	}
+	return nil
+}
+
+func writeRankingSheet(wb *xlsx.File) error {
+	sheet, err := wb.AddSheet("Calories per Dollar ranking")
+	if err != nil {
+		return fmt.Errorf("error creating ranking sheet: %v", err)
+	}
+
+	ranked := make([]Food, len(foods))
+	copy(ranked, foods)
+	for i := 0; i < len(ranked)-1; i++ {
+		for j := 0; j < len(ranked)-i-1; j++ {
+			if ranked[j].CalPerDollar < ranked[j+1].CalPerDollar {
+				ranked[j], ranked[j+1] = ranked[j+1], ranked[j]
+			}
+		}
+	}
+
+	writeHeaderRow(sheet, []string{"Rank", "Name", "Cal/Dollar"})
+	for i, f := range ranked {
+		row := sheet.AddRow()
+		row.AddCell().SetInt(i + 1)
+		row.AddCell().SetValue(f.Name)
+		row.AddCell().SetFloat(f.CalPerDollar)
+	}
+	return nil
+}
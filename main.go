@@ -2,16 +2,13 @@ package main
 
 import (
 	"bufio"
-	"encoding/json"
+	"context"
 	"fmt"
 	"log"
 	"os"
 	"strconv"
 	"strings"
 	"time"
-
-	ui "github.com/gizak/termui/v3"
-	"github.com/gizak/termui/v3/widgets"
 )
 
 const (
@@ -21,6 +18,7 @@ const (
 
 type Food struct {
 	ID           int     `json:"id"`
+	UserID       string  `json:"user_id"`
 	Name         string  `json:"name"`
 	Price        float64 `json:"price"`
 	Calories     float64 `json:"calories"`
@@ -31,6 +29,7 @@ type Food struct {
 
 type DiaryEntry struct {
 	ID       int     `json:"id"`
+	UserID   string  `json:"user_id"`
 	Date     string  `json:"date"`
 	FoodID   int     `json:"food_id"`
 	FoodName string  `json:"food_name"`
@@ -44,75 +43,40 @@ type DailyDiary struct {
 }
 
 var (
-	foods      []Food
-	dailyDiary DailyDiary
+	foods            []Food
+	dailyDiary       DailyDiary
+	currentUser      User
+	currentStore     *UserStore
+	currentDataStore Store
 )
 
-// Save and load functions for the food database
+// Save and load functions for the food database, delegating to
+// currentDataStore so the backing format (JSON file or SQLite) can change
+// without touching any of the menu-handling code below.
 func saveToFile() error {
-	file, err := os.Create(dataFile)
-	if err != nil {
-		return fmt.Errorf("error creating file: %v", err)
-	}
-	defer file.Close()
-
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "    ")
-	if err := encoder.Encode(foods); err != nil {
-		return fmt.Errorf("error encoding data: %v", err)
-	}
-	return nil
+	return currentDataStore.SaveFoods(foods)
 }
 
 func loadFromFile() error {
-	file, err := os.Open(dataFile)
+	loaded, err := currentDataStore.LoadFoods()
 	if err != nil {
-		if os.IsNotExist(err) {
-			foods = make([]Food, 0)
-			return nil
-		}
-		return fmt.Errorf("error opening file: %v", err)
-	}
-	defer file.Close()
-
-	decoder := json.NewDecoder(file)
-	if err := decoder.Decode(&foods); err != nil {
-		return fmt.Errorf("error decoding data: %v", err)
+		return err
 	}
+	foods = loaded
 	return nil
 }
 
-// Save and load functions for the diary
+// Save and load functions for the diary, delegating to currentDataStore.
 func saveDiaryToFile() error {
-	file, err := os.Create(diaryFile)
-	if err != nil {
-		return fmt.Errorf("error creating diary file: %v", err)
-	}
-	defer file.Close()
-
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "    ")
-	if err := encoder.Encode(dailyDiary); err != nil {
-		return fmt.Errorf("error encoding diary data: %v", err)
-	}
-	return nil
+	return currentDataStore.SaveDiary(dailyDiary)
 }
 
 func loadDiaryFromFile() error {
-	file, err := os.Open(diaryFile)
+	loaded, err := currentDataStore.LoadDiary()
 	if err != nil {
-		if os.IsNotExist(err) {
-			dailyDiary.Entries = make([]DiaryEntry, 0)
-			return nil
-		}
-		return fmt.Errorf("error opening diary file: %v", err)
-	}
-	defer file.Close()
-
-	decoder := json.NewDecoder(file)
-	if err := decoder.Decode(&dailyDiary); err != nil {
-		return fmt.Errorf("error decoding diary data: %v", err)
+		return err
 	}
+	dailyDiary = loaded
 	return nil
 }
 
@@ -164,6 +128,7 @@ func addFoodToDatabase() {
 		}
 	}
 	food.ID = maxID + 1
+	food.UserID = currentUser.Username
 
 	foods = append(foods, food)
 
@@ -283,6 +248,7 @@ func addFoodToDiary() {
 	// Create diary entry
 	entry := DiaryEntry{
 		ID:       len(dailyDiary.Entries) + 1,
+		UserID:   currentUser.Username,
 		Date:     time.Now().Format("2006-01-02"),
 		FoodID:   selectedFood.ID,
 		FoodName: selectedFood.Name,
@@ -309,16 +275,17 @@ func viewDiary() {
 		dateStr = time.Now().Format("2006-01-02")
 	}
 
-	var dayEntries []DiaryEntry
+	dayEntries, err := currentDataStore.QueryDiaryByDate(dateStr)
+	if err != nil {
+		fmt.Printf("Error querying diary: %v\n", err)
+		return
+	}
+
 	var totalCals float64
 	var totalCost float64
-
-	for _, entry := range dailyDiary.Entries {
-		if entry.Date == dateStr {
-			dayEntries = append(dayEntries, entry)
-			totalCals += entry.Calories
-			totalCost += entry.Cost
-		}
+	for _, entry := range dayEntries {
+		totalCals += entry.Calories
+		totalCost += entry.Cost
 	}
 
 	if len(dayEntries) == 0 {
@@ -344,7 +311,11 @@ func showFoodMenu() {
 	fmt.Println("3. View diary")
 	fmt.Println("4. Search foods")
 	fmt.Println("5. View stats")
-	fmt.Println("6. Return to Main Menu")
+	fmt.Println("6. Import foods (CSV/XLSX)")
+	fmt.Println("7. Export foods (CSV/XLSX)")
+	fmt.Println("8. Import diary (CSV)")
+	fmt.Println("9. Launch dashboard")
+	fmt.Println("10. Return to Main Menu")
 	fmt.Print("Choose an option: ")
 }
 
@@ -354,7 +325,8 @@ func showMainMenu() {
 	fmt.Println("2. Symptom Tracking")
 	fmt.Println("3. Compare")
 	fmt.Println("4. Finances")
-	fmt.Println("5. Exit")
+	fmt.Println("5. Background Task Status")
+	fmt.Println("6. Exit")
 	fmt.Print("Choose an Option by typing the number: ")
 }
 
@@ -362,43 +334,36 @@ func compareTrackMenu() {
 	fmt.Println("\n=== Compare Track Menu ===")
 	fmt.Println("What would you like to compare:\n ")
 	fmt.Println("1. Compare diet and symptoms")
+	fmt.Println("2. Diet/symptom correlation analysis")
 	fmt.Print("Choose an Option by typing the number: ")
 }
 
 func compareDietSymptoms() {
-	fmt.Println("Compare Diet and Symptoms")
-	if err := ui.Init(); err != nil {
-		log.Fatalf("failed to initialize termui: %v", err)
-	}
-	defer ui.Close()
-
-	p := widgets.NewParagraph()
-	p.Text = "Hello World!"
-	p.SetRect(0, 0, 25, 5)
-
-	ui.Render(p)
-
-	for e := range ui.PollEvents() {
-		if e.Type == ui.KeyboardEvent {
-			break
-		}
+	if err := runDashboard(); err != nil {
+		fmt.Printf("Dashboard error: %v\n", err)
 	}
 }
 
 func handleCompareMenu() {
 	for {
+		drainMainJobs()
 		compareTrackMenu()
 		choice := readInput("")
 
 		switch choice {
 		case "1":
 			compareDietSymptoms()
+		case "2":
+			if err := runCorrelationDashboard(); err != nil {
+				fmt.Printf("Correlation dashboard error: %v\n", err)
+			}
 		}
 	}
 }
 
 func handleFoodMenu() {
 	for {
+		drainMainJobs()
 		showFoodMenu()
 		choice := readInput("")
 
@@ -414,6 +379,14 @@ func handleFoodMenu() {
 		case "5":
 			viewStats()
 		case "6":
+			handleFoodImport()
+		case "7":
+			handleFoodExport()
+		case "8":
+			handleDiaryImport()
+		case "9":
+			compareDietSymptoms()
+		case "10":
 			return
 		default:
 			fmt.Println("Invalid option. Please try again.")
@@ -421,7 +394,78 @@ func handleFoodMenu() {
 	}
 }
 
+// handleFoodImport prompts for a CSV or XLSX path and imports it into the
+// food database, dispatching on file extension.
+func handleFoodImport() {
+	path := readInput("Path to CSV or XLSX file to import: ")
+	var err error
+	if strings.HasSuffix(strings.ToLower(path), ".xlsx") {
+		err = importFoodsXLSX(path)
+	} else {
+		err = importFoodsCSV(path)
+	}
+	if err != nil {
+		fmt.Printf("Import failed: %v\n", err)
+		return
+	}
+	fmt.Println("Import complete.")
+}
+
+// handleFoodExport prompts for a destination path and exports the food
+// database as CSV or XLSX, dispatching on file extension.
+func handleFoodExport() {
+	path := readInput("Path to write CSV or XLSX file: ")
+	var err error
+	if strings.HasSuffix(strings.ToLower(path), ".xlsx") {
+		err = exportFoodsXLSX(path)
+	} else {
+		err = exportFoodsCSV(path)
+	}
+	if err != nil {
+		fmt.Printf("Export failed: %v\n", err)
+		return
+	}
+	fmt.Println("Export complete.")
+}
+
+// handleDiaryImport prompts for a CSV path and imports diary entries.
+func handleDiaryImport() {
+	path := readInput("Path to diary CSV file to import: ")
+	if err := importDiaryCSV(path); err != nil {
+		fmt.Printf("Import failed: %v\n", err)
+		return
+	}
+	fmt.Println("Import complete.")
+}
+
 func main() {
+	// Log in and resolve the per-user data directory before touching any
+	// food or diary data.
+	currentUser = loginFlow()
+	store, err := NewUserStore(currentUser.Username)
+	if err != nil {
+		log.Fatalf("Failed to set up user data directory: %v", err)
+	}
+	currentStore = store
+
+	// `go-track migrate` is a one-shot command that copies the legacy
+	// JSON files into a SQLite database for the logged-in user and exits.
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrateLegacyData(currentStore.DataDir); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
+
+	dataStore, err := openDataStore(currentStore.DataDir)
+	if err != nil {
+		log.Fatalf("Failed to open data store: %v", err)
+	}
+	currentDataStore = dataStore
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	// Load existing data
 
 	//symptom data
@@ -434,18 +478,34 @@ func main() {
 		log.Printf("Warning: Failed to load existing symptom diary data: %v", err)
 		symptomDiary.Entries = make([]SymptomEntry, 0)
 	}
-	//food data
-	if err := loadFromFile(); err != nil {
-		log.Printf("Warning: Failed to load existing food data: %v", err)
-		foods = make([]Food, 0)
+
+	// Pull any foods/diary pushed from another device before falling
+	// back to this device's own local copy, so two devices sharing an
+	// account reconcile onto the same data instead of silently
+	// diverging.
+	if !syncPullAtLogin(ctx) {
+		//food data
+		if err := loadFromFile(); err != nil {
+			log.Printf("Warning: Failed to load existing food data: %v", err)
+			foods = make([]Food, 0)
+		}
+
+		if err := loadDiaryFromFile(); err != nil {
+			log.Printf("Warning: Failed to load existing diary data: %v", err)
+			dailyDiary.Entries = make([]DiaryEntry, 0)
+		}
 	}
 
-	if err := loadDiaryFromFile(); err != nil {
-		log.Printf("Warning: Failed to load existing diary data: %v", err)
-		dailyDiary.Entries = make([]DiaryEntry, 0)
+	// Background tasks (rolling averages, derived-field recompute, diary
+	// pruning, sync push) run for the lifetime of the process.
+	runner, err := startBackgroundTasks(ctx)
+	if err != nil {
+		log.Printf("Warning: Failed to start background tasks: %v", err)
 	}
+	backgroundRunner = runner
 
 	for {
+		drainMainJobs()
 		showMainMenu()
 		choice := readInput("")
 
@@ -459,6 +519,8 @@ func main() {
 		case "4":
 			HandleFinanceMenu()
 		case "5":
+			showTaskStatus()
+		case "6":
 			fmt.Println("Goodbye!")
 			return
 		default:
@@ -0,0 +1,348 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	_ "modernc.org/sqlite"
+)
+
+const sqliteFile = "go-track.db"
+
+// openDataStore picks the Store implementation for dataDir: once
+// migrateLegacyData has created a go-track.db there, it's used for
+// every subsequent run; otherwise the app falls back to the original
+// JSONStore, so the one-shot migration actually takes effect instead of
+// being a dead end.
+func openDataStore(dataDir string) (Store, error) {
+	if _, err := os.Stat(filepath.Join(dataDir, sqliteFile)); err == nil {
+		return NewSQLiteStore(dataDir)
+	}
+	return NewJSONStore(dataDir), nil
+}
+
+// Store abstracts persistence for foods and the diary so the backing
+// format (flat JSON files today, SQLite below) can change without
+// touching the menu-handling code in main.go.
+type Store interface {
+	SaveFoods(foods []Food) error
+	LoadFoods() ([]Food, error)
+	SaveDiary(diary DailyDiary) error
+	LoadDiary() (DailyDiary, error)
+	QueryDiaryByDate(date string) ([]DiaryEntry, error)
+}
+
+// JSONStore is the original flat-file implementation, scoped to a user's
+// data directory.
+type JSONStore struct {
+	DataDir string
+}
+
+func NewJSONStore(dataDir string) *JSONStore {
+	return &JSONStore{DataDir: dataDir}
+}
+
+func (s *JSONStore) foodsPath() string {
+	return filepath.Join(s.DataDir, dataFile)
+}
+
+func (s *JSONStore) diaryPath() string {
+	return filepath.Join(s.DataDir, diaryFile)
+}
+
+func (s *JSONStore) SaveFoods(foods []Food) error {
+	file, err := os.Create(s.foodsPath())
+	if err != nil {
+		return fmt.Errorf("error creating file: %v", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "    ")
+	if err := encoder.Encode(foods); err != nil {
+		return fmt.Errorf("error encoding data: %v", err)
+	}
+	return nil
+}
+
+func (s *JSONStore) LoadFoods() ([]Food, error) {
+	file, err := os.Open(s.foodsPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return make([]Food, 0), nil
+		}
+		return nil, fmt.Errorf("error opening file: %v", err)
+	}
+	defer file.Close()
+
+	var loaded []Food
+	decoder := json.NewDecoder(file)
+	if err := decoder.Decode(&loaded); err != nil {
+		return nil, fmt.Errorf("error decoding data: %v", err)
+	}
+	return loaded, nil
+}
+
+func (s *JSONStore) SaveDiary(diary DailyDiary) error {
+	file, err := os.Create(s.diaryPath())
+	if err != nil {
+		return fmt.Errorf("error creating diary file: %v", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "    ")
+	if err := encoder.Encode(diary); err != nil {
+		return fmt.Errorf("error encoding diary data: %v", err)
+	}
+	return nil
+}
+
+func (s *JSONStore) LoadDiary() (DailyDiary, error) {
+	file, err := os.Open(s.diaryPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return DailyDiary{Entries: make([]DiaryEntry, 0)}, nil
+		}
+		return DailyDiary{}, fmt.Errorf("error opening diary file: %v", err)
+	}
+	defer file.Close()
+
+	var diary DailyDiary
+	decoder := json.NewDecoder(file)
+	if err := decoder.Decode(&diary); err != nil {
+		return DailyDiary{}, fmt.Errorf("error decoding diary data: %v", err)
+	}
+	return diary, nil
+}
+
+func (s *JSONStore) QueryDiaryByDate(date string) ([]DiaryEntry, error) {
+	diary, err := s.LoadDiary()
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []DiaryEntry
+	for _, e := range diary.Entries {
+		if e.Date == date {
+			matched = append(matched, e)
+		}
+	}
+	return matched, nil
+}
+
+// SQLiteStore backs foods and diary entries with a SQLite database,
+// indexing diary entries by date and food_id so QueryDiaryByDate (used
+// by viewDiary) reads only the rows for one date. SaveFoods and SaveDiary
+// still delete and re-insert every row of their table on each save.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens (creating if necessary) the SQLite database in
+// dataDir and ensures its schema is up to date.
+func NewSQLiteStore(dataDir string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", filepath.Join(dataDir, sqliteFile))
+	if err != nil {
+		return nil, fmt.Errorf("error opening sqlite database: %v", err)
+	}
+
+	store := &SQLiteStore{db: db}
+	if err := store.migrateSchema(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *SQLiteStore) migrateSchema() error {
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS foods (
+			id INTEGER PRIMARY KEY,
+			user_id TEXT,
+			name TEXT NOT NULL,
+			price REAL,
+			calories REAL,
+			quantity INTEGER,
+			cal_per_dollar REAL,
+			cal_per_100g REAL
+		)`,
+		`CREATE TABLE IF NOT EXISTS diary_entries (
+			id INTEGER PRIMARY KEY,
+			user_id TEXT,
+			date TEXT NOT NULL,
+			food_id INTEGER NOT NULL,
+			food_name TEXT,
+			quantity INTEGER,
+			calories REAL,
+			cost REAL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_diary_date ON diary_entries(date)`,
+		`CREATE INDEX IF NOT EXISTS idx_diary_food_id ON diary_entries(food_id)`,
+	}
+
+	for _, stmt := range statements {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return fmt.Errorf("error running schema migration: %v", err)
+		}
+	}
+	return nil
+}
+
+func (s *SQLiteStore) SaveFoods(foods []Food) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM foods`); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("error clearing foods: %v", err)
+	}
+
+	stmt, err := tx.Prepare(`INSERT INTO foods
+		(id, user_id, name, price, calories, quantity, cal_per_dollar, cal_per_100g)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("error preparing insert: %v", err)
+	}
+	defer stmt.Close()
+
+	for _, f := range foods {
+		if _, err := stmt.Exec(f.ID, f.UserID, f.Name, f.Price, f.Calories, f.Quantity, f.CalPerDollar, f.CalPer100g); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("error inserting food: %v", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLiteStore) LoadFoods() ([]Food, error) {
+	rows, err := s.db.Query(`SELECT id, user_id, name, price, calories, quantity, cal_per_dollar, cal_per_100g FROM foods`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying foods: %v", err)
+	}
+	defer rows.Close()
+
+	loaded := make([]Food, 0)
+	for rows.Next() {
+		var f Food
+		if err := rows.Scan(&f.ID, &f.UserID, &f.Name, &f.Price, &f.Calories, &f.Quantity, &f.CalPerDollar, &f.CalPer100g); err != nil {
+			return nil, fmt.Errorf("error scanning food: %v", err)
+		}
+		loaded = append(loaded, f)
+	}
+	return loaded, rows.Err()
+}
+
+func (s *SQLiteStore) SaveDiary(diary DailyDiary) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM diary_entries`); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("error clearing diary entries: %v", err)
+	}
+
+	stmt, err := tx.Prepare(`INSERT INTO diary_entries
+		(id, user_id, date, food_id, food_name, quantity, calories, cost)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("error preparing insert: %v", err)
+	}
+	defer stmt.Close()
+
+	for _, e := range diary.Entries {
+		if _, err := stmt.Exec(e.ID, e.UserID, e.Date, e.FoodID, e.FoodName, e.Quantity, e.Calories, e.Cost); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("error inserting diary entry: %v", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLiteStore) LoadDiary() (DailyDiary, error) {
+	rows, err := s.db.Query(`SELECT id, user_id, date, food_id, food_name, quantity, calories, cost FROM diary_entries`)
+	if err != nil {
+		return DailyDiary{}, fmt.Errorf("error querying diary entries: %v", err)
+	}
+	defer rows.Close()
+
+	diary := DailyDiary{Entries: make([]DiaryEntry, 0)}
+	for rows.Next() {
+		var e DiaryEntry
+		if err := rows.Scan(&e.ID, &e.UserID, &e.Date, &e.FoodID, &e.FoodName, &e.Quantity, &e.Calories, &e.Cost); err != nil {
+			return DailyDiary{}, fmt.Errorf("error scanning diary entry: %v", err)
+		}
+		diary.Entries = append(diary.Entries, e)
+	}
+	return diary, rows.Err()
+}
+
+func (s *SQLiteStore) QueryDiaryByDate(date string) ([]DiaryEntry, error) {
+	rows, err := s.db.Query(`SELECT id, user_id, date, food_id, food_name, quantity, calories, cost
+		FROM diary_entries WHERE date = ? ORDER BY id`, date)
+	if err != nil {
+		return nil, fmt.Errorf("error querying diary entries by date: %v", err)
+	}
+	defer rows.Close()
+
+	var matched []DiaryEntry
+	for rows.Next() {
+		var e DiaryEntry
+		if err := rows.Scan(&e.ID, &e.UserID, &e.Date, &e.FoodID, &e.FoodName, &e.Quantity, &e.Calories, &e.Cost); err != nil {
+			return nil, fmt.Errorf("error scanning diary entry: %v", err)
+		}
+		matched = append(matched, e)
+	}
+	return matched, rows.Err()
+}
+
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// migrateLegacyData is the one-shot migration path from the legacy
+// foods_data.json / diary_data.json files to a SQLiteStore. It is invoked
+// via `go-track migrate` rather than through the interactive menus, and
+// migrates the data directory of the user who logs in, since it's a
+// single operation users run once per data directory.
+func migrateLegacyData(dataDir string) error {
+	legacy := NewJSONStore(dataDir)
+
+	foods, err := legacy.LoadFoods()
+	if err != nil {
+		return fmt.Errorf("error loading legacy foods: %v", err)
+	}
+
+	diary, err := legacy.LoadDiary()
+	if err != nil {
+		return fmt.Errorf("error loading legacy diary: %v", err)
+	}
+
+	sqliteStore, err := NewSQLiteStore(dataDir)
+	if err != nil {
+		return fmt.Errorf("error opening sqlite store: %v", err)
+	}
+	defer sqliteStore.Close()
+
+	if err := sqliteStore.SaveFoods(foods); err != nil {
+		return fmt.Errorf("error migrating foods: %v", err)
+	}
+	if err := sqliteStore.SaveDiary(diary); err != nil {
+		return fmt.Errorf("error migrating diary: %v", err)
+	}
+
+	fmt.Printf("Migrated %d foods and %d diary entries into %s\n",
+		len(foods), len(diary.Entries), filepath.Join(dataDir, sqliteFile))
+	return nil
+}
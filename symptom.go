@@ -0,0 +1,257 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	symptomFile      = "symptoms_data.json"
+	symptomDiaryFile = "symptom_diary_data.json"
+)
+
+type Symptom struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type SymptomEntry struct {
+	ID          int    `json:"id"`
+	UserID      string `json:"user_id"`
+	Date        string `json:"date"`
+	SymptomName string `json:"symptom_name"`
+	Severity    int    `json:"severity"` // 1-10
+}
+
+type SymptomDiary struct {
+	Symptoms []Symptom      `json:"symptoms"`
+	Entries  []SymptomEntry `json:"entries"`
+}
+
+var symptomDiary SymptomDiary
+
+func symptomPath() string {
+	return filepath.Join(currentStore.DataDir, symptomFile)
+}
+
+func symptomDiaryPath() string {
+	return filepath.Join(currentStore.DataDir, symptomDiaryFile)
+}
+
+// Save and load functions for the symptom list.
+func saveSymptomData() error {
+	file, err := os.Create(symptomPath())
+	if err != nil {
+		return fmt.Errorf("error creating symptom file: %v", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "    ")
+	if err := encoder.Encode(symptomDiary.Symptoms); err != nil {
+		return fmt.Errorf("error encoding symptom data: %v", err)
+	}
+	return nil
+}
+
+func loadSymptomData() error {
+	file, err := os.Open(symptomPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			symptomDiary.Symptoms = make([]Symptom, 0)
+			return nil
+		}
+		return fmt.Errorf("error opening symptom file: %v", err)
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	if err := decoder.Decode(&symptomDiary.Symptoms); err != nil {
+		return fmt.Errorf("error decoding symptom data: %v", err)
+	}
+	return nil
+}
+
+// Save and load functions for logged symptom entries.
+func saveSymptomDiaryData() error {
+	file, err := os.Create(symptomDiaryPath())
+	if err != nil {
+		return fmt.Errorf("error creating symptom diary file: %v", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "    ")
+	if err := encoder.Encode(symptomDiary.Entries); err != nil {
+		return fmt.Errorf("error encoding symptom diary data: %v", err)
+	}
+	return nil
+}
+
+func loadSymptomDiaryData() error {
+	file, err := os.Open(symptomDiaryPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			symptomDiary.Entries = make([]SymptomEntry, 0)
+			return nil
+		}
+		return fmt.Errorf("error opening symptom diary file: %v", err)
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	if err := decoder.Decode(&symptomDiary.Entries); err != nil {
+		return fmt.Errorf("error decoding symptom diary data: %v", err)
+	}
+	return nil
+}
+
+func addSymptomToDatabase() {
+	name := readInput("Symptom name (e.g. headache): ")
+	if name == "" {
+		fmt.Println("Symptom name cannot be empty.")
+		return
+	}
+
+	maxID := 0
+	for _, s := range symptomDiary.Symptoms {
+		if s.ID > maxID {
+			maxID = s.ID
+		}
+	}
+
+	symptomDiary.Symptoms = append(symptomDiary.Symptoms, Symptom{ID: maxID + 1, Name: name})
+	if err := saveSymptomData(); err != nil {
+		log.Printf("Warning: Failed to save symptom data: %v", err)
+	}
+
+	fmt.Printf("\nAdded symptom: %s\n", name)
+}
+
+func logSymptomEntry() {
+	if len(symptomDiary.Symptoms) == 0 {
+		fmt.Println("No symptoms added yet. Add one first.")
+		return
+	}
+
+	query := readInput("Enter symptom name to search: ")
+	query = strings.ToLower(query)
+
+	var matched []Symptom
+	for _, s := range symptomDiary.Symptoms {
+		if strings.Contains(strings.ToLower(s.Name), query) {
+			matched = append(matched, s)
+			fmt.Printf("%d. %s\n", s.ID, s.Name)
+		}
+	}
+	if len(matched) == 0 {
+		fmt.Println("No symptoms found matching your search.")
+		return
+	}
+
+	idStr := readInput("Enter the ID of the symptom you want to log: ")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		fmt.Println("Invalid symptom ID.")
+		return
+	}
+
+	var selected Symptom
+	found := false
+	for _, s := range matched {
+		if s.ID == id {
+			selected = s
+			found = true
+			break
+		}
+	}
+	if !found {
+		fmt.Println("Symptom ID not found in search results.")
+		return
+	}
+
+	severityStr := readInput("Severity (1-10): ")
+	severity, err := strconv.Atoi(severityStr)
+	if err != nil || severity < 1 || severity > 10 {
+		fmt.Println("Invalid severity. Please enter a number from 1 to 10.")
+		return
+	}
+
+	dateStr := readInput("Date (YYYY-MM-DD) or press Enter for today: ")
+	if dateStr == "" {
+		dateStr = time.Now().Format("2006-01-02")
+	}
+
+	symptomDiary.Entries = append(symptomDiary.Entries, SymptomEntry{
+		ID:          len(symptomDiary.Entries) + 1,
+		UserID:      currentUser.Username,
+		Date:        dateStr,
+		SymptomName: selected.Name,
+		Severity:    severity,
+	})
+	if err := saveSymptomDiaryData(); err != nil {
+		log.Printf("Warning: Failed to save symptom diary: %v", err)
+	}
+
+	fmt.Printf("\nLogged %s (severity %d) on %s\n", selected.Name, severity, dateStr)
+}
+
+func viewSymptomDiary() {
+	dateStr := readInput("Enter date (YYYY-MM-DD) or press Enter for today: ")
+	if dateStr == "" {
+		dateStr = time.Now().Format("2006-01-02")
+	}
+
+	var dayEntries []SymptomEntry
+	for _, e := range symptomDiary.Entries {
+		if e.Date == dateStr {
+			dayEntries = append(dayEntries, e)
+		}
+	}
+
+	if len(dayEntries) == 0 {
+		fmt.Printf("No symptoms logged for %s\n", dateStr)
+		return
+	}
+
+	fmt.Printf("\nSymptoms for %s:\n", dateStr)
+	for _, e := range dayEntries {
+		fmt.Printf("%s: %d/10\n", e.SymptomName, e.Severity)
+	}
+}
+
+func showSymptomMenu() {
+	fmt.Println("\n=== Symptom Tracker Menu ===")
+	fmt.Println("1. Add new symptom")
+	fmt.Println("2. Log symptom entry")
+	fmt.Println("3. View symptom diary")
+	fmt.Println("4. Return to Main Menu")
+	fmt.Print("Choose an option: ")
+}
+
+func HandleSymptomMenu() {
+	for {
+		drainMainJobs()
+		showSymptomMenu()
+		choice := readInput("")
+
+		switch choice {
+		case "1":
+			addSymptomToDatabase()
+		case "2":
+			logSymptomEntry()
+		case "3":
+			viewSymptomDiary()
+		case "4":
+			return
+		default:
+			fmt.Println("Invalid option. Please try again.")
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// syncBaseURL is the default reference sync endpoint used by the
+// background sync-push task. A self-hosted deployment would override
+// this, but there's no config plumbing for it yet.
+const syncBaseURL = "https://sync.go-track.example"
+
+// SyncBackend lets a UserStore's data be shared across devices. Push sends
+// the local foods/diary up to the backend; Pull replaces the local copy
+// with whatever the backend currently holds.
+type SyncBackend interface {
+	Push(ctx context.Context, foods []Food, diary DailyDiary) error
+	Pull(ctx context.Context) ([]Food, DailyDiary, error)
+}
+
+// syncPayload is the wire format exchanged with an HTTPSyncBackend.
+type syncPayload struct {
+	Foods []Food     `json:"foods"`
+	Diary DailyDiary `json:"diary"`
+}
+
+// HTTPSyncBackend is the reference SyncBackend: it stores a user's foods
+// and diary as JSON on a remote HTTP endpoint, e.g. a small REST service
+// fronting shared household storage.
+type HTTPSyncBackend struct {
+	BaseURL string
+	User    User
+	Client  *http.Client
+}
+
+// NewHTTPSyncBackend builds a backend that pushes to and pulls from
+// baseURL+"/users/<username>/data", authenticating with the user's token
+// when present.
+func NewHTTPSyncBackend(baseURL string, user User) *HTTPSyncBackend {
+	return &HTTPSyncBackend{
+		BaseURL: baseURL,
+		User:    user,
+		Client:  &http.Client{},
+	}
+}
+
+func (b *HTTPSyncBackend) endpoint() string {
+	return fmt.Sprintf("%s/users/%s/data", b.BaseURL, b.User.Username)
+}
+
+func (b *HTTPSyncBackend) Push(ctx context.Context, foods []Food, diary DailyDiary) error {
+	body, err := json.Marshal(syncPayload{Foods: foods, Diary: diary})
+	if err != nil {
+		return fmt.Errorf("error encoding sync payload: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, b.endpoint(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error building push request: %v", err)
+	}
+	b.setAuth(req)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error pushing sync data: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("sync push failed with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (b *HTTPSyncBackend) Pull(ctx context.Context) ([]Food, DailyDiary, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.endpoint(), nil)
+	if err != nil {
+		return nil, DailyDiary{}, fmt.Errorf("error building pull request: %v", err)
+	}
+	b.setAuth(req)
+
+	resp, err := b.Client.Do(req)
+	if err != nil {
+		return nil, DailyDiary{}, fmt.Errorf("error pulling sync data: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return nil, DailyDiary{}, fmt.Errorf("sync pull failed with status %d", resp.StatusCode)
+	}
+
+	var payload syncPayload
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, DailyDiary{}, fmt.Errorf("error decoding sync payload: %v", err)
+	}
+	return payload.Foods, payload.Diary, nil
+}
+
+// syncPullAtLogin pulls the logged-in user's foods/diary from their
+// configured sync backend, if any, and makes the pulled data the
+// in-memory (and on-disk) state for this session. It reports whether a
+// pull actually replaced local state, so main can skip its own
+// loadFromFile/loadDiaryFromFile in that case. Without a token, or on
+// any pull error, it's a no-op and the caller falls back to local data.
+func syncPullAtLogin(ctx context.Context) bool {
+	if currentUser.Token == "" {
+		return false
+	}
+
+	backend := NewHTTPSyncBackend(syncBaseURL, currentUser)
+	pulledFoods, pulledDiary, err := backend.Pull(ctx)
+	if err != nil {
+		log.Printf("Warning: Failed to pull synced data: %v", err)
+		return false
+	}
+
+	foods = pulledFoods
+	dailyDiary = pulledDiary
+
+	if err := saveToFile(); err != nil {
+		log.Printf("Warning: Failed to persist pulled foods: %v", err)
+	}
+	if err := saveDiaryToFile(); err != nil {
+		log.Printf("Warning: Failed to persist pulled diary: %v", err)
+	}
+	return true
+}
+
+func (b *HTTPSyncBackend) setAuth(req *http.Request) {
+	if b.User.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+b.User.Token)
+	}
+}
@@ -0,0 +1,163 @@
+// Package task runs config-driven background tasks (rolling averages,
+// recomputing derived food fields, pruning stale diary data, periodic
+// sync) concurrently under a context.Context, and exposes their
+// last-run status so the main menu can surface it to the user.
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Duration parses from a Go duration string ("24h", "90m") in
+// config.json, matching the update_interval/timeout fields.
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("error unmarshaling duration: %v", err)
+	}
+
+	parsed, err := time.ParseDuration(raw)
+	if err != nil {
+		return fmt.Errorf("error parsing duration %q: %v", raw, err)
+	}
+	d.Duration = parsed
+	return nil
+}
+
+// CommandSpec accepts either a bare shell string ("sync-push") or a
+// struct with an explicit name and args in config.json.
+type CommandSpec struct {
+	Name string
+	Args []string
+}
+
+func (c *CommandSpec) UnmarshalJSON(data []byte) error {
+	var bare string
+	if err := json.Unmarshal(data, &bare); err == nil {
+		c.Name = bare
+		c.Args = nil
+		return nil
+	}
+
+	var full struct {
+		Name string   `json:"name"`
+		Args []string `json:"args"`
+	}
+	if err := json.Unmarshal(data, &full); err != nil {
+		return fmt.Errorf("error unmarshaling command: %v", err)
+	}
+	c.Name = full.Name
+	c.Args = full.Args
+	return nil
+}
+
+// Config is the shape of config.json: a flat list of task declarations.
+type Config struct {
+	Tasks []TaskConfig `json:"tasks"`
+}
+
+// TaskConfig declares a single background task: how often it runs, how
+// long it's allowed to take, and which registered command it invokes.
+type TaskConfig struct {
+	Name           string      `json:"name"`
+	UpdateInterval Duration    `json:"update_interval"`
+	Timeout        Duration    `json:"timeout"`
+	Command        CommandSpec `json:"command"`
+}
+
+// Func is the work a task performs. It must respect ctx's deadline.
+type Func func(ctx context.Context) error
+
+// Task pairs a TaskConfig with the Func that implements it.
+type Task struct {
+	Config TaskConfig
+	Run    Func
+}
+
+// Status reports the outcome of a task's most recent run.
+type Status struct {
+	Name    string
+	LastRun time.Time
+	LastErr error
+}
+
+// Runner executes a fixed set of tasks concurrently, each on its own
+// UpdateInterval ticker, until its context is cancelled.
+type Runner struct {
+	mu     sync.Mutex
+	tasks  []Task
+	status map[string]Status
+}
+
+// NewRunner builds a Runner for tasks. Tasks with a zero UpdateInterval
+// are kept in the status report but never started by Start, since they
+// have nothing configured to drive them.
+func NewRunner(tasks []Task) *Runner {
+	status := make(map[string]Status, len(tasks))
+	for _, t := range tasks {
+		status[t.Config.Name] = Status{Name: t.Config.Name}
+	}
+	return &Runner{tasks: tasks, status: status}
+}
+
+// Start runs every task on its own ticker until ctx is cancelled. It
+// returns immediately; tasks run in background goroutines.
+func (r *Runner) Start(ctx context.Context) {
+	for _, t := range r.tasks {
+		if t.Config.UpdateInterval.Duration <= 0 {
+			continue
+		}
+		go r.loop(ctx, t)
+	}
+}
+
+func (r *Runner) loop(ctx context.Context, t Task) {
+	ticker := time.NewTicker(t.Config.UpdateInterval.Duration)
+	defer ticker.Stop()
+
+	r.runOnce(ctx, t)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.runOnce(ctx, t)
+		}
+	}
+}
+
+func (r *Runner) runOnce(ctx context.Context, t Task) {
+	runCtx := ctx
+	var cancel context.CancelFunc
+	if t.Config.Timeout.Duration > 0 {
+		runCtx, cancel = context.WithTimeout(ctx, t.Config.Timeout.Duration)
+		defer cancel()
+	}
+
+	err := t.Run(runCtx)
+
+	r.mu.Lock()
+	r.status[t.Config.Name] = Status{Name: t.Config.Name, LastRun: time.Now(), LastErr: err}
+	r.mu.Unlock()
+}
+
+// StatusReport returns the last-run time and error for every task, in the
+// order the tasks were registered.
+func (r *Runner) StatusReport() []Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := make([]Status, 0, len(r.tasks))
+	for _, t := range r.tasks {
+		report = append(report, r.status[t.Config.Name])
+	}
+	return report
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// User identifies the active account for this session. Token is optional
+// and only needed when a SyncBackend requires authentication.
+type User struct {
+	Username string
+	Token    string
+}
+
+// UserStore scopes the food database and diary to a single user's data
+// directory, following the XDG-ish layout ~/.go-track/<user>/.
+type UserStore struct {
+	Username string
+	DataDir  string
+}
+
+// userDataDir returns the per-user data directory, creating it if needed.
+func userDataDir(username string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("error resolving home directory: %v", err)
+	}
+
+	dir := filepath.Join(home, ".go-track", username)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("error creating user data directory: %v", err)
+	}
+	return dir, nil
+}
+
+// NewUserStore resolves and creates the data directory for username.
+func NewUserStore(username string) (*UserStore, error) {
+	dir, err := userDataDir(username)
+	if err != nil {
+		return nil, err
+	}
+	return &UserStore{Username: username, DataDir: dir}, nil
+}
+
+// loginFlow prompts for a username and an optional sync token at startup.
+// An empty token means the session will run without cloud sync.
+func loginFlow() User {
+	fmt.Println("\n=== go-track Login ===")
+	username := readInput("Username: ")
+	for username == "" {
+		fmt.Println("Username cannot be empty.")
+		username = readInput("Username: ")
+	}
+
+	token := readInput("Sync token (leave blank to skip cloud sync): ")
+
+	return User{Username: username, Token: token}
+}